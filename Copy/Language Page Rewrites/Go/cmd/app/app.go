@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/airbrake/gobrake"
+)
+
+var projectID int64 = 144783
+var projectAPIKey = "f1c9d04409afd9001a2f7c328120c864"
+
+// Create notifier instance via NotifierOptions so we can set the
+// environment/revision/host up front instead of filtering every notice.
+var notifier = gobrake.NewNotifierWithOptions(&gobrake.NotifierOptions{
+	ProjectId:   projectID,
+	ProjectKey:  projectAPIKey,
+	Environment: "development",
+	Revision:    "38f5dfe2c8b87f1ef1f7b93a01a3f4b47f9a0e1a",
+	// Host points gobrake at a self-hosted Errbit instance instead of
+	// the default api.airbrake.io endpoint.
+	Host: "https://errbit.example.com",
+	// Any Params, Environment or Session value under a matching key is
+	// replaced with "[Filtered]" before the notice is sent.
+	KeysBlacklist: []interface{}{
+		"password",
+		regexp.MustCompile(`(?i)token`),
+	},
+	HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	// Notices are batched and flushed every FlushInterval, or as soon as
+	// MaxBatchSize is reached, whichever comes first.
+	MaxBatchSize:  100,
+	FlushInterval: 5 * time.Second,
+	// Spool persists notices to disk whenever the Airbrake host can't be
+	// reached, so a flaky network (or shutdown mid-flush) can't lose
+	// anything still waiting to be sent. They're replayed on next startup.
+	Spool: gobrake.NewFileSpool(filepath.Join(os.TempDir(), "gobrake")),
+})
+
+func main() {
+	// Always close notifier
+	defer notifier.Close()
+	// Always notify on panic
+	defer notifier.NotifyOnPanic()
+
+	// Attach ~3 lines of surrounding source to each backtrace frame.
+	notifier.SetCodeHunks(true)
+	notifier.MaxCodeHunkSize = 3
+
+	// Create a new error and send via notifier
+	notifier.Notify(errors.New("oh oh, something broke"), nil)
+}
@@ -22,6 +22,19 @@ func main() {
 	// Set glog instance
 	glog.Gobrake = notifier
 
+	// Map glog levels to Airbrake severities, and drop anything below
+	// WARNING so routine INFO logging doesn't spend our error quota.
+	glog.SeverityMap = glog.DefaultSeverityMap
+	glog.MinSeverity = glog.WARNING
+
+	// NotifyFunc runs on every notice glog builds, before it's sent; here
+	// it just tags the notice so it's easy to filter by source on the
+	// Airbrake dashboard.
+	glog.NotifyFunc = func(notice *gobrake.Notice) *gobrake.Notice {
+		notice.Context["component"] = "glog"
+		return notice
+	}
+
 	// Create a new error and log it with glog
 	glog.Errorf("Error logged: %s", errors.New("uh oh, something broke"))
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/airbrake/gobrake"
+	"github.com/airbrake/gobrake/httpbrake"
+)
+
+var projectID int64 = 144783
+var projectAPIKey = "f1c9d04409afd9001a2f7c328120c864"
+
+// Create notifier instance with Project ID and Project API Keys
+var notifier = gobrake.NewNotifier(projectID, projectAPIKey)
+
+func main() {
+	// Always close notifier
+	defer notifier.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+
+	// Wrap the mux so panics are reported as notices (with the request
+	// URL, method, headers and route params attached) and per-route
+	// latency is aggregated and flushed to Airbrake every ~15s.
+	http.Handle("/", httpbrake.Middleware(notifier, mux))
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,92 @@
+// Package glog is a small, self-contained stand-in for
+// github.com/airbrake/glog, vendored locally so the Go language-page
+// sample can exercise severity mapping and the rate-limit fallback
+// described on the page.
+package glog
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/airbrake/gobrake"
+)
+
+// Level is a glog severity level.
+type Level int
+
+const (
+	INFO Level = iota
+	WARNING
+	ERROR
+	FATAL
+)
+
+// DefaultSeverityMap maps every glog Level to the Airbrake severity
+// written into Notice.Context["severity"].
+var DefaultSeverityMap = map[Level]string{
+	INFO:    "info",
+	WARNING: "warning",
+	ERROR:   "error",
+	FATAL:   "critical",
+}
+
+// SeverityMap controls what severity string is attached to each level.
+// Defaults to DefaultSeverityMap.
+var SeverityMap = DefaultSeverityMap
+
+// MinSeverity is the lowest level that's actually sent to Airbrake; levels
+// below it are logged locally and dropped. Defaults to INFO, i.e. nothing
+// is dropped.
+var MinSeverity = INFO
+
+// NotifyFunc, if set, runs on every notice glog builds before it's sent,
+// so callers can annotate it (e.g. with the glog call site).
+var NotifyFunc func(*gobrake.Notice) *gobrake.Notice
+
+// Gobrake is the notifier glog reports through.
+var Gobrake *gobrake.Notifier
+
+func log(level Level, err error) {
+	if level < MinSeverity || Gobrake == nil {
+		return
+	}
+
+	notice := gobrake.NewNotice(err)
+	if severity, ok := SeverityMap[level]; ok {
+		notice.Context["severity"] = severity
+	}
+	if NotifyFunc != nil {
+		notice = NotifyFunc(notice)
+		if notice == nil {
+			return
+		}
+	}
+
+	// SendNotice is used instead of Notify so we get an error back
+	// immediately: if Airbrake is rate limiting us, the log line still
+	// needs to go somewhere rather than vanish inside a dropped notice.
+	if err := Gobrake.SendNotice(notice); err != nil && gobrake.IsRateLimited(err) {
+		fmt.Fprintln(os.Stderr, notice.Errors[0].Message)
+	}
+}
+
+// Infof logs at INFO severity.
+func Infof(format string, args ...interface{}) {
+	log(INFO, fmt.Errorf(format, args...))
+}
+
+// Warningf logs at WARNING severity.
+func Warningf(format string, args ...interface{}) {
+	log(WARNING, fmt.Errorf(format, args...))
+}
+
+// Errorf logs at ERROR severity.
+func Errorf(format string, args ...interface{}) {
+	log(ERROR, fmt.Errorf(format, args...))
+}
+
+// Fatalf logs at FATAL severity, then exits.
+func Fatalf(format string, args ...interface{}) {
+	log(FATAL, fmt.Errorf(format, args...))
+	os.Exit(1)
+}
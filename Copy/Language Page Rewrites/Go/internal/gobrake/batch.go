@@ -0,0 +1,143 @@
+package gobrake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxBatchSize  = 10
+	defaultFlushInterval = 2 * time.Second
+	maxSendAttempts      = 5
+	initialBackoff       = 500 * time.Millisecond
+)
+
+// batchLoop drains noticeCh, packing up to MaxBatchSize notices (or
+// FlushInterval's worth, whichever comes first) into a single send. It
+// exits once Close closes n.closing, draining whatever is still buffered
+// in noticeCh before flushing the final batch and closing batchDone.
+// noticeCh itself is never closed, so Notify can never race a close()
+// panic here.
+func (n *Notifier) batchLoop() {
+	defer close(n.batchDone)
+
+	size := n.opts.MaxBatchSize
+	if size <= 0 {
+		size = defaultMaxBatchSize
+	}
+	interval := n.opts.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var batch []*Notice
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		n.sendBatch(batch)
+		batch = nil
+	}
+
+	for {
+		select {
+		case notice := <-n.noticeCh:
+			batch = append(batch, notice)
+			if len(batch) >= size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-n.closing:
+			// Drain whatever's already queued without blocking, then send
+			// it all as one final batch.
+			for {
+				select {
+				case notice := <-n.noticeCh:
+					batch = append(batch, notice)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendBatch POSTs batch as a single gzip-compressed body, honoring
+// Retry-After on HTTP 429 with exponential backoff and jitter. If every
+// attempt fails, the batch is spooled instead of dropped.
+func (n *Notifier) sendBatch(batch []*Notice) {
+	body, err := gzipEncode(batch)
+	if err != nil {
+		n.spoolBatch(batch)
+		return
+	}
+
+	backoff := initialBackoff
+	for attempt := 0; attempt < maxSendAttempts; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, n.createNoticeURL, bytes.NewReader(body))
+		if err != nil {
+			break
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+
+		resp, err := n.httpClient.Do(req)
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header.Get("Retry-After"), backoff)
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return
+		}
+		break
+	}
+
+	n.spoolBatch(batch)
+}
+
+// retryAfter honors a numeric Retry-After header when present, otherwise
+// falls back to backoff, and adds up to 50% jitter either way so a burst
+// of clients don't all retry in lockstep.
+func retryAfter(header string, backoff time.Duration) time.Duration {
+	wait := backoff
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		wait = time.Duration(secs) * time.Second
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/2+1))
+}
+
+func gzipEncode(batch []*Notice) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(batch); err != nil {
+		gz.Close()
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Notifier) spoolBatch(batch []*Notice) {
+	for _, notice := range batch {
+		n.spool(notice)
+	}
+}
@@ -0,0 +1,150 @@
+package gobrake
+
+import (
+	"bufio"
+	"container/list"
+	"os"
+	"sync"
+)
+
+// SetCodeHunks toggles whether Notify/NotifyOnPanic attach surrounding
+// source lines to each backtrace frame.
+func (n *Notifier) SetCodeHunks(enabled bool) {
+	n.mu.Lock()
+	n.codeHunks = enabled
+	n.mu.Unlock()
+}
+
+func (n *Notifier) codeHunksEnabled() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.codeHunks
+}
+
+func (n *Notifier) maxCodeHunkSize() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.MaxCodeHunkSize <= 0 {
+		return 2
+	}
+	return n.MaxCodeHunkSize
+}
+
+// hunkKey identifies a cached file read; mtime is part of the key so an
+// edited file (e.g. during `go run`) doesn't serve stale lines.
+type hunkKey struct {
+	file  string
+	mtime int64
+}
+
+// hunkCache is an LRU of file contents keyed by (file, mtime), so hot
+// frames in a repeated panic/error loop don't re-read their file from
+// disk on every occurrence.
+type hunkCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[hunkKey]*list.Element
+}
+
+type hunkCacheEntry struct {
+	key   hunkKey
+	lines []string
+}
+
+func newHunkCache(capacity int) *hunkCache {
+	return &hunkCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[hunkKey]*list.Element),
+	}
+}
+
+// hunk returns up to 2*span+1 lines of source centered on line. Files that
+// don't exist on disk (common in stripped production binaries) are
+// skipped rather than treated as an error.
+func (c *hunkCache) hunk(file string, line, span int) map[int]string {
+	if file == "" || line <= 0 {
+		return nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil
+	}
+	key := hunkKey{file: file, mtime: info.ModTime().UnixNano()}
+
+	if lines, ok := c.get(key); ok {
+		return sliceHunk(lines, line, span)
+	}
+
+	lines, err := readLines(file)
+	if err != nil {
+		return nil
+	}
+	c.put(key, lines)
+	return sliceHunk(lines, line, span)
+}
+
+func (c *hunkCache) get(key hunkKey) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*hunkCacheEntry).lines, true
+}
+
+func (c *hunkCache) put(key hunkKey, lines []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el := c.ll.PushFront(&hunkCacheEntry{key: key, lines: lines})
+	c.items[key] = el
+
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*hunkCacheEntry).key)
+	}
+}
+
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func sliceHunk(lines []string, line, span int) map[int]string {
+	start := line - span
+	if start < 1 {
+		start = 1
+	}
+	end := line + span
+	if end > len(lines) {
+		end = len(lines)
+	}
+	if start > end {
+		return nil
+	}
+
+	code := make(map[int]string, end-start+1)
+	for i := start; i <= end; i++ {
+		code[i] = lines[i-1]
+	}
+	return code
+}
@@ -0,0 +1,368 @@
+// Package gobrake is a small, self-contained stand-in for
+// github.com/airbrake/gobrake, vendored locally so the Go language-page
+// samples in this repo can exercise the notifier options described on the
+// page without depending on an unreleased upstream version.
+package gobrake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// errRateLimited is returned by SendNotice when Airbrake responds 429. Use
+// IsRateLimited to test for it from another package.
+var errRateLimited = errors.New("gobrake: rate limited")
+
+// IsRateLimited reports whether err is (or wraps) a rate-limit response
+// from Airbrake.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, errRateLimited)
+}
+
+// Notice is the payload gobrake sends to Airbrake for a single error.
+type Notice struct {
+	Errors      []NoticeError          `json:"errors"`
+	Context     map[string]interface{} `json:"context"`
+	Environment map[string]interface{} `json:"environment"`
+	Params      map[string]interface{} `json:"params"`
+	Session     map[string]interface{} `json:"session"`
+}
+
+// NoticeError describes the error being reported.
+type NoticeError struct {
+	Type      string  `json:"type"`
+	Message   string  `json:"message"`
+	Backtrace []Frame `json:"backtrace,omitempty"`
+}
+
+// Frame is a single backtrace entry.
+type Frame struct {
+	File string         `json:"file"`
+	Line int            `json:"line"`
+	Func string         `json:"function"`
+	Code map[int]string `json:"code,omitempty"`
+}
+
+// NewNotice builds an empty Notice wrapping err.
+func NewNotice(err error) *Notice {
+	return &Notice{
+		Errors: []NoticeError{{
+			Type:    fmt.Sprintf("%T", err),
+			Message: err.Error(),
+		}},
+		Context:     map[string]interface{}{},
+		Environment: map[string]interface{}{},
+		Params:      map[string]interface{}{},
+		Session:     map[string]interface{}{},
+	}
+}
+
+// Filter mutates notice before it's sent, or discards it by returning nil.
+type Filter func(notice *Notice) *Notice
+
+// NotifierOptions configures NewNotifierWithOptions.
+type NotifierOptions struct {
+	ProjectId  int64
+	ProjectKey string
+
+	// Environment and Revision are merged into every Notice.Context, so
+	// callers don't need an AddFilter closure for either.
+	Environment string
+	Revision    string
+
+	// Host points gobrake at a self-hosted Errbit-compatible instance (or
+	// a reverse proxy) instead of the default api.airbrake.io endpoint.
+	Host string
+
+	// KeysBlacklist holds strings and/or *regexp.Regexp values; any
+	// Params, Environment or Session key that matches has its value
+	// replaced with "[Filtered]" before the notice is sent.
+	KeysBlacklist []interface{}
+
+	HTTPClient *http.Client
+
+	// MaxBatchSize and FlushInterval bound how long a notice waits before
+	// being sent: it goes out as soon as MaxBatchSize notices have piled
+	// up, or FlushInterval has elapsed, whichever comes first.
+	MaxBatchSize  int
+	FlushInterval time.Duration
+
+	// Spool persists notices the batch sender couldn't deliver (network
+	// down, Airbrake unreachable) so they survive a restart and are
+	// replayed on the next NewNotifierWithOptions call. Defaults to no
+	// spooling; use NewFileSpool for the bundled disk-backed spool.
+	Spool Spool
+}
+
+// Notifier sends error notices to Airbrake.
+type Notifier struct {
+	opts            NotifierOptions
+	httpClient      *http.Client
+	createNoticeURL string
+
+	mu      sync.Mutex
+	filters []Filter
+
+	codeHunks       bool
+	MaxCodeHunkSize int
+	hunkCache       *hunkCache
+
+	// noticeCh feeds the batching worker started by NewNotifierWithOptions.
+	// It is never closed: Notify would otherwise race Close's close() and
+	// panic with "send on closed channel". Instead Close closes closing,
+	// which the worker also selects on; closeOnce makes a second Close
+	// call a no-op rather than a double-close panic on closing itself.
+	noticeCh  chan *Notice
+	closing   chan struct{}
+	closeOnce sync.Once
+	batchDone chan struct{}
+}
+
+// NewNotifier creates a Notifier for the given project.
+func NewNotifier(projectId int64, projectKey string) *Notifier {
+	return NewNotifierWithOptions(&NotifierOptions{
+		ProjectId:  projectId,
+		ProjectKey: projectKey,
+	})
+}
+
+// NewNotifierWithOptions creates a Notifier from opts.
+func NewNotifierWithOptions(opts *NotifierOptions) *Notifier {
+	o := *opts
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+
+	n := &Notifier{
+		opts:            o,
+		httpClient:      o.HTTPClient,
+		createNoticeURL: buildCreateNoticeURL(o.Host, o.ProjectId, o.ProjectKey),
+		hunkCache:       newHunkCache(256),
+		noticeCh:        make(chan *Notice, 1000),
+		closing:         make(chan struct{}),
+		batchDone:       make(chan struct{}),
+	}
+
+	if o.Environment != "" || o.Revision != "" {
+		n.AddFilter(func(notice *Notice) *Notice {
+			if o.Environment != "" {
+				notice.Context["environment"] = o.Environment
+			}
+			if o.Revision != "" {
+				notice.Context["revision"] = o.Revision
+			}
+			return notice
+		})
+	}
+
+	// Replay anything left over from a previous run before we start
+	// accepting new notices.
+	if o.Spool != nil {
+		if pending, err := o.Spool.Drain(); err == nil {
+			for _, notice := range pending {
+				n.noticeCh <- notice
+			}
+		}
+	}
+
+	go n.batchLoop()
+
+	return n
+}
+
+// AddFilter registers a filter run over every notice before it's sent.
+func (n *Notifier) AddFilter(filter Filter) {
+	n.mu.Lock()
+	n.filters = append(n.filters, filter)
+	n.mu.Unlock()
+}
+
+func (n *Notifier) applyFilters(notice *Notice) *Notice {
+	n.mu.Lock()
+	filters := append([]Filter(nil), n.filters...)
+	n.mu.Unlock()
+
+	for _, filter := range filters {
+		notice = filter(notice)
+		if notice == nil {
+			return nil
+		}
+	}
+	return notice
+}
+
+// prepare runs notice through the registered filters and redacts any
+// blacklisted keys. It's the shared step before a notice is either sent
+// immediately (SendNotice) or handed to the batch queue (Notify).
+func (n *Notifier) prepare(notice *Notice) *Notice {
+	notice = n.applyFilters(notice)
+	if notice == nil {
+		return nil
+	}
+	redact(notice, n.opts.KeysBlacklist)
+	return notice
+}
+
+// populateRequest copies the request's URL, method and headers into
+// notice so dashboard users can see what was being served when it failed.
+func populateRequest(notice *Notice, req *http.Request) {
+	if req == nil {
+		return
+	}
+	notice.Environment["url"] = req.URL.String()
+	notice.Environment["method"] = req.Method
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+	notice.Params["headers"] = headers
+}
+
+// Notify builds a Notice for err and queues it for the batch sender;
+// delivery errors are not reported back to the caller. If the queue is
+// momentarily full the notice is spooled straight to disk instead of
+// blocking the caller.
+func (n *Notifier) Notify(err error, req *http.Request) {
+	notice := NewNotice(err)
+	notice.Errors[0].Backtrace = n.buildBacktrace()
+	populateRequest(notice, req)
+
+	notice = n.prepare(notice)
+	if notice == nil {
+		return
+	}
+
+	select {
+	case <-n.closing:
+		// Close has started; don't hand the worker anything new, it may
+		// already be flushing its final batch.
+		n.spool(notice)
+	case n.noticeCh <- notice:
+	default:
+		n.spool(notice)
+	}
+}
+
+// buildBacktrace walks the caller's stack and, if code hunks are enabled,
+// attaches ~MaxCodeHunkSize lines of surrounding source to each frame.
+func (n *Notifier) buildBacktrace() []Frame {
+	var pcs [64]uintptr
+	// Skip runtime.Callers, buildBacktrace and Notify/NotifyOnPanic
+	// themselves so the first frame is the caller's.
+	count := runtime.Callers(4, pcs[:])
+	if count == 0 {
+		return nil
+	}
+
+	withHunks := n.codeHunksEnabled()
+	span := n.maxCodeHunkSize()
+
+	frames := runtime.CallersFrames(pcs[:count])
+	var backtrace []Frame
+	for {
+		f, more := frames.Next()
+		frame := Frame{File: f.File, Line: f.Line, Func: f.Function}
+		if withHunks {
+			frame.Code = n.hunkCache.hunk(f.File, f.Line, span)
+		}
+		backtrace = append(backtrace, frame)
+		if !more {
+			break
+		}
+	}
+	return backtrace
+}
+
+// NotifyOnPanic recovers a panic, if any, and reports it. It's meant to be
+// deferred right after the notifier is created.
+func (n *Notifier) NotifyOnPanic() {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		n.Notify(err, nil)
+		panic(r)
+	}
+}
+
+// HTTPClient returns the client the notifier sends notices with, so
+// subpackages like httpbrake can reuse it for their own requests.
+func (n *Notifier) HTTPClient() *http.Client {
+	return n.httpClient
+}
+
+// RoutesStatsURL returns the endpoint httpbrake should flush per-route
+// latency stats to, honoring NotifierOptions.Host the same way
+// SendNotice's create-notice URL does.
+func (n *Notifier) RoutesStatsURL() string {
+	return buildRoutesStatsURL(n.opts.Host, n.opts.ProjectId)
+}
+
+// Close signals the batch worker to flush whatever it still has queued,
+// and waits for that final flush to finish. Unlike a naive "one goroutine
+// per Notify" sender, this can't block forever on a downed network: a
+// flush that fails spools its batch and returns. Close is idempotent and
+// safe to call concurrently with Notify.
+func (n *Notifier) Close() error {
+	n.closeOnce.Do(func() { close(n.closing) })
+	<-n.batchDone
+	return nil
+}
+
+// SendNotice delivers a single notice synchronously, redacting blacklisted
+// keys first, and returns any delivery error (errRateLimited on HTTP 429).
+// It bypasses the batch queue, so callers that need an immediate result
+// (glog's rate-limit fallback, for instance) can use it directly.
+func (n *Notifier) SendNotice(notice *Notice) error {
+	notice = n.prepare(notice)
+	if notice == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.createNoticeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return errRateLimited
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("gobrake: unexpected response: %s", resp.Status)
+	}
+	return nil
+}
+
+// spool hands notice to the configured Spool, if any; it's a no-op when
+// the caller didn't set NotifierOptions.Spool. A notice that can't even be
+// spooled (disk full, spool directory gone) is the last line of defense
+// before it's lost for good, so the failure is reported to stderr rather
+// than discarded.
+func (n *Notifier) spool(notice *Notice) {
+	if n.opts.Spool == nil {
+		return
+	}
+	if err := n.opts.Spool.Push(notice); err != nil {
+		fmt.Fprintf(os.Stderr, "gobrake: failed to spool notice: %v\n", err)
+	}
+}
@@ -0,0 +1,38 @@
+package httpbrake
+
+import (
+	"net/http"
+
+	"github.com/airbrake/gobrake"
+)
+
+// NegroniMiddleware adapts Middleware to Negroni's native middleware
+// signature, so it can be registered directly:
+//
+//	n := negroni.New()
+//	n.UseFunc(httpbrake.NegroniMiddleware(notifier))
+func NegroniMiddleware(notifier *gobrake.Notifier) func(http.ResponseWriter, *http.Request, http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		Middleware(notifier, next).ServeHTTP(w, r)
+	}
+}
+
+// GinHandler, BeegoHandler and FiberHandler return the same instrumented
+// http.Handler as Middleware. Gin, Beego and Fiber all support mounting a
+// plain http.Handler directly, so no framework-specific translation is
+// needed beyond picking the right mount point:
+//
+//	router.Any("/*path", gin.WrapH(httpbrake.GinHandler(notifier, mux)))
+//	beego.Handler("/*", httpbrake.BeegoHandler(notifier, mux))
+//	app.Use(adaptor.HTTPHandler(httpbrake.FiberHandler(notifier, mux)))
+func GinHandler(notifier *gobrake.Notifier, next http.Handler) http.Handler {
+	return Middleware(notifier, next)
+}
+
+func BeegoHandler(notifier *gobrake.Notifier, next http.Handler) http.Handler {
+	return Middleware(notifier, next)
+}
+
+func FiberHandler(notifier *gobrake.Notifier, next http.Handler) http.Handler {
+	return Middleware(notifier, next)
+}
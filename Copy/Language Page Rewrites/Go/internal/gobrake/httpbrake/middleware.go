@@ -0,0 +1,41 @@
+// Package httpbrake wraps an http.Handler so panics are reported to
+// Airbrake and per-route latency is aggregated into the routes-stats
+// endpoint described on the Go language page.
+package httpbrake
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/airbrake/gobrake"
+)
+
+// Middleware recovers panics from next as notices (with the request URL,
+// method and headers attached) and records per-route latency for
+// periodic flushing to notifier's routes-stats endpoint. Each notifier
+// gets its own aggregator and flush loop, so wrapping handlers for two
+// different notifiers never mixes their route stats.
+func Middleware(notifier *gobrake.Notifier, next http.Handler) http.Handler {
+	stats := statsFor(notifier)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := r.URL.Path
+
+		defer func() {
+			stats.record(route, time.Since(start))
+
+			if rec := recover(); rec != nil {
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+				notifier.Notify(err, r)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,193 @@
+package httpbrake
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/airbrake/gobrake"
+)
+
+// numShards bounds how many routes can be updated concurrently without
+// contending on the same bucket lock; a route is always hashed to the
+// same shard, so a given route's updates still serialize on its own
+// bucket, just never on another route's.
+const numShards = 16
+
+const flushInterval = 15 * time.Second
+
+// routeBucket accumulates latency samples for a single route. Counters
+// use atomics so record() never blocks; only the small reservoir used for
+// the p95 estimate takes a lock.
+type routeBucket struct {
+	count       uint64
+	sumMillis   uint64
+	sumSqMillis uint64
+
+	mu      sync.Mutex
+	samples []float64
+}
+
+type shard struct {
+	mu      sync.Mutex
+	buckets map[string]*routeBucket
+}
+
+type aggregator struct {
+	shards [numShards]*shard
+}
+
+func newAggregator() *aggregator {
+	a := &aggregator{}
+	for i := range a.shards {
+		a.shards[i] = &shard{buckets: make(map[string]*routeBucket)}
+	}
+	return a
+}
+
+var (
+	statsMu         sync.Mutex
+	statsByNotifier = map[*gobrake.Notifier]*aggregator{}
+)
+
+// statsFor returns notifier's aggregator, creating it (and starting its
+// flush loop) the first time notifier is seen.
+func statsFor(notifier *gobrake.Notifier) *aggregator {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	a, ok := statsByNotifier[notifier]
+	if !ok {
+		a = newAggregator()
+		statsByNotifier[notifier] = a
+		go a.loop(notifier)
+	}
+	return a
+}
+
+func shardFor(route string) int {
+	h := fnv.New32a()
+	h.Write([]byte(route))
+	return int(h.Sum32() % numShards)
+}
+
+func (a *aggregator) bucket(route string) *routeBucket {
+	s := a.shards[shardFor(route)]
+
+	s.mu.Lock()
+	b, ok := s.buckets[route]
+	if !ok {
+		b = &routeBucket{}
+		s.buckets[route] = b
+	}
+	s.mu.Unlock()
+
+	return b
+}
+
+// record adds one latency sample for route. It only ever locks the bucket
+// belonging to route's shard, so requests against other routes (almost
+// always a different shard) never contend with it.
+func (a *aggregator) record(route string, d time.Duration) {
+	b := a.bucket(route)
+
+	ms := float64(d.Milliseconds())
+	atomic.AddUint64(&b.count, 1)
+	atomic.AddUint64(&b.sumMillis, uint64(ms))
+	atomic.AddUint64(&b.sumSqMillis, uint64(ms*ms))
+
+	const maxSamples = 200
+	b.mu.Lock()
+	if len(b.samples) < maxSamples {
+		b.samples = append(b.samples, ms)
+	}
+	b.mu.Unlock()
+}
+
+type routeStat struct {
+	Route string  `json:"route"`
+	Count uint64  `json:"count"`
+	Sum   uint64  `json:"sum_ms"`
+	SumSq uint64  `json:"sum_sq_ms"`
+	P95   float64 `json:"p95_ms"`
+}
+
+// flush merges every shard's buckets into one payload, resets their
+// counters for the next window, and POSTs the result to url.
+func (a *aggregator) flush(client *http.Client, url string) {
+	var stats []routeStat
+	for _, s := range a.shards {
+		s.mu.Lock()
+		routes := s.buckets
+		s.mu.Unlock()
+
+		for route, b := range routes {
+			b.mu.Lock()
+			samples := b.samples
+			b.samples = nil
+			b.mu.Unlock()
+
+			count := atomic.SwapUint64(&b.count, 0)
+			if count == 0 {
+				continue
+			}
+			stats = append(stats, routeStat{
+				Route: route,
+				Count: count,
+				Sum:   atomic.SwapUint64(&b.sumMillis, 0),
+				SumSq: atomic.SwapUint64(&b.sumSqMillis, 0),
+				P95:   percentile(samples, 0.95),
+			})
+		}
+	}
+
+	if len(stats) == 0 {
+		return
+	}
+	postStats(client, url, stats)
+}
+
+func postStats(client *http.Client, url string, stats []routeStat) {
+	body, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+func percentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// loop flushes the aggregator every flushInterval until the process exits.
+func (a *aggregator) loop(notifier *gobrake.Notifier) {
+	url := notifier.RoutesStatsURL()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.flush(notifier.HTTPClient(), url)
+	}
+}
@@ -0,0 +1,62 @@
+package gobrake
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redact walks notice.Params, notice.Environment and notice.Session and
+// replaces the value of any key matching blacklist with "[Filtered]", so
+// passwords/tokens don't leak to Airbrake.
+func redact(notice *Notice, blacklist []interface{}) {
+	if len(blacklist) == 0 {
+		return
+	}
+	redactMap(notice.Params, blacklist)
+	redactMap(notice.Environment, blacklist)
+	redactMap(notice.Session, blacklist)
+}
+
+func redactMap(m map[string]interface{}, blacklist []interface{}) {
+	for key, value := range m {
+		if keyBlacklisted(key, blacklist) {
+			m[key] = "[Filtered]"
+			continue
+		}
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			redactMap(nested, blacklist)
+		case map[string]string:
+			// e.g. Notice.Params["headers"], which populateRequest fills
+			// with concrete string values rather than interface{} ones.
+			redactStringMap(nested, blacklist)
+		}
+	}
+}
+
+func redactStringMap(m map[string]string, blacklist []interface{}) {
+	for key := range m {
+		if keyBlacklisted(key, blacklist) {
+			m[key] = "[Filtered]"
+		}
+	}
+}
+
+// keyBlacklisted reports whether key matches an entry in blacklist. Each
+// entry is either a string (case-insensitive exact match) or a
+// *regexp.Regexp.
+func keyBlacklisted(key string, blacklist []interface{}) bool {
+	for _, entry := range blacklist {
+		switch v := entry.(type) {
+		case string:
+			if strings.EqualFold(v, key) {
+				return true
+			}
+		case *regexp.Regexp:
+			if v.MatchString(key) {
+				return true
+			}
+		}
+	}
+	return false
+}
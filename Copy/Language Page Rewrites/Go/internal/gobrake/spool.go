@@ -0,0 +1,123 @@
+package gobrake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+func init() {
+	// Notice.Params/Environment/Session store arbitrary values behind
+	// interface{}; populateRequest puts a concrete map[string]string
+	// (the request headers) in there, and gob refuses to encode an
+	// unregistered concrete type behind an interface without this.
+	gob.Register(map[string]string{})
+}
+
+// Spool persists notices that couldn't be delivered (network down,
+// process shutting down mid-flush) so they can be replayed on next
+// startup.
+type Spool interface {
+	Push(notice *Notice) error
+	Drain() ([]*Notice, error)
+}
+
+// FileSpool is the default Spool: a bounded directory of length-prefixed
+// gob-encoded notices.
+type FileSpool struct {
+	dir      string
+	maxFiles int
+
+	mu sync.Mutex
+}
+
+// NewFileSpool returns a FileSpool rooted at dir, creating it if needed.
+func NewFileSpool(dir string) *FileSpool {
+	os.MkdirAll(dir, 0o755)
+	return &FileSpool{dir: dir, maxFiles: 1000}
+}
+
+// Push appends notice as a new length-prefixed gob file in the spool
+// directory.
+func (s *FileSpool) Push(notice *Notice) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) >= s.maxFiles {
+		return fmt.Errorf("gobrake: spool %s is full", s.dir)
+	}
+
+	f, err := os.CreateTemp(s.dir, "notice-*.gob")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(notice); err != nil {
+		return err
+	}
+
+	var lenPrefix [4]byte
+	binary.BigEndian.PutUint32(lenPrefix[:], uint32(buf.Len()))
+	if _, err := f.Write(lenPrefix[:]); err != nil {
+		return err
+	}
+	_, err = f.Write(buf.Bytes())
+	return err
+}
+
+// Drain reads and removes every spooled notice, returning them for replay.
+func (s *FileSpool) Drain() ([]*Notice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var notices []*Notice
+	for _, e := range entries {
+		path := filepath.Join(s.dir, e.Name())
+		if notice, err := readSpooledNotice(path); err == nil {
+			notices = append(notices, notice)
+		}
+		os.Remove(path)
+	}
+	return notices, nil
+}
+
+func readSpooledNotice(path string) (*Notice, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lenPrefix [4]byte
+	if _, err := io.ReadFull(f, lenPrefix[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(lenPrefix[:])
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+
+	var notice Notice
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&notice); err != nil {
+		return nil, err
+	}
+	return &notice, nil
+}
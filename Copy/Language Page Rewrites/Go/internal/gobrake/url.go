@@ -0,0 +1,30 @@
+package gobrake
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const defaultHost = "https://api.airbrake.io"
+
+// buildCreateNoticeURL returns the endpoint a single notice is POSTed to.
+// When host is empty it targets Airbrake's hosted API; otherwise it
+// targets a self-hosted Errbit-compatible instance (or reverse proxy) at
+// host instead.
+func buildCreateNoticeURL(host string, projectId int64, projectKey string) string {
+	if host == "" {
+		host = defaultHost
+	}
+	endpoint := fmt.Sprintf("%s/api/v3/projects/%d/notices", strings.TrimRight(host, "/"), projectId)
+	return endpoint + "?key=" + url.QueryEscape(projectKey)
+}
+
+// buildRoutesStatsURL returns the endpoint httpbrake flushes per-route
+// latency stats to.
+func buildRoutesStatsURL(host string, projectId int64) string {
+	if host == "" {
+		host = defaultHost
+	}
+	return fmt.Sprintf("%s/api/v5/projects/%d/routes-stats", strings.TrimRight(host, "/"), projectId)
+}